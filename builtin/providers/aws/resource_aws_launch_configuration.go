@@ -7,15 +7,78 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/aws-sdk-go/aws"
 	"github.com/hashicorp/aws-sdk-go/gen/autoscaling"
+	"github.com/hashicorp/aws-sdk-go/gen/ec2"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
+var amiIDPattern = regexp.MustCompile(`^ami-[0-9a-f]{8}([0-9a-f]{9})?$`)
+
+// validEc2InstanceTypes is a static allow-list used for plan-time validation
+// of instance_type. This vendored helper/schema does not expose a
+// CustomizeDiff hook with API access, so a live DescribeImages-backed
+// virtualization cross-check isn't available here -- an instance_type that
+// is on this list but unsupported by the AMI still only fails at apply,
+// same as a nonexistent image_id or iam_instance_profile.
+var validEc2InstanceTypes = map[string]bool{
+	"t1.micro":    true,
+	"t2.nano":     true,
+	"t2.micro":    true,
+	"t2.small":    true,
+	"t2.medium":   true,
+	"t2.large":    true,
+	"m1.small":    true,
+	"m1.medium":   true,
+	"m1.large":    true,
+	"m1.xlarge":   true,
+	"m2.xlarge":   true,
+	"m2.2xlarge":  true,
+	"m2.4xlarge":  true,
+	"m3.medium":   true,
+	"m3.large":    true,
+	"m3.xlarge":   true,
+	"m3.2xlarge":  true,
+	"m4.large":    true,
+	"m4.xlarge":   true,
+	"m4.2xlarge":  true,
+	"m4.4xlarge":  true,
+	"m4.10xlarge": true,
+	"c1.medium":   true,
+	"c1.xlarge":   true,
+	"c3.large":    true,
+	"c3.xlarge":   true,
+	"c3.2xlarge":  true,
+	"c3.4xlarge":  true,
+	"c3.8xlarge":  true,
+	"c4.large":    true,
+	"c4.xlarge":   true,
+	"c4.2xlarge":  true,
+	"c4.4xlarge":  true,
+	"c4.8xlarge":  true,
+	"r3.large":    true,
+	"r3.xlarge":   true,
+	"r3.2xlarge":  true,
+	"r3.4xlarge":  true,
+	"r3.8xlarge":  true,
+	"i2.xlarge":   true,
+	"i2.2xlarge":  true,
+	"i2.4xlarge":  true,
+	"i2.8xlarge":  true,
+	"d2.xlarge":   true,
+	"d2.2xlarge":  true,
+	"d2.4xlarge":  true,
+	"d2.8xlarge":  true,
+	"g2.2xlarge":  true,
+	"g2.8xlarge":  true,
+}
+
 func resourceAwsLaunchConfiguration() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAwsLaunchConfigurationCreate,
@@ -24,8 +87,16 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"name_prefix"},
+			},
+
+			"name_prefix": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 				ForceNew: true,
 			},
 
@@ -33,14 +104,36 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					value := v.(string)
+					if !amiIDPattern.MatchString(value) {
+						errors = append(errors, fmt.Errorf(
+							"%q must be a valid AMI id (ami-xxxxxxxx), got %q", k, value))
+					}
+					return
+				},
 			},
 
 			"instance_type": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					value := v.(string)
+					if !validEc2InstanceTypes[value] {
+						errors = append(errors, fmt.Errorf(
+							"%q is not a recognized EC2 instance type: %q", k, value))
+					}
+					return
+				},
 			},
 
+			// Note: existence checks for iam_instance_profile and image_id
+			// require an AWS API round-trip against the provider's
+			// connections, which ValidateFunc has no access to. image_id is
+			// only format-checked above and iam_instance_profile is not
+			// validated at plan time; a missing profile or image still
+			// surfaces as an apply-time error from Create.
 			"iam_instance_profile": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -91,6 +184,33 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"enable_monitoring": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				ForceNew: true,
+			},
+
+			"ebs_optimized": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"placement_tenancy": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					value := v.(string)
+					if value != "default" && value != "dedicated" {
+						errors = append(errors, fmt.Errorf(
+							"%q must be one of \"default\" or \"dedicated\", got %q", k, value))
+					}
+					return
+				},
+			},
+
 			"block_device": &schema.Schema{
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -143,6 +263,20 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 							Computed: true,
 							ForceNew: true,
 						},
+
+						"encrypted": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+
+						"kms_key_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
 					},
 				},
 				Set: func(v interface{}) int {
@@ -157,6 +291,12 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 					buf.WriteString(fmt.Sprintf("%s-", m["snapshot_id"].(string)))
 					buf.WriteString(fmt.Sprintf("%d-", m["volume_size"].(int)))
 					buf.WriteString(fmt.Sprintf("%s-", m["volume_type"].(string)))
+					// NOTE: Not considering encrypted in hash either -- it's
+					// Computed and can be populated by AWS independently of
+					// what the user set (e.g. account-level default
+					// encryption), which would throw off the set calculation
+					// the same way IOPS does above.
+					// buf.WriteString(fmt.Sprintf("%t-", m["encrypted"].(bool)))
 					return hashcode.String(buf.String())
 				},
 			},
@@ -228,16 +368,32 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 							Computed: true,
 							ForceNew: true,
 						},
+
+						"encrypted": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+
+						"kms_key_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
 					},
 				},
 				Set: func(v interface{}) int {
 					var buf bytes.Buffer
 					m := v.(map[string]interface{})
 					buf.WriteString(fmt.Sprintf("%t-", m["delete_on_termination"].(bool)))
-					// See the NOTE in "ebs_block_device" for why we skip iops here.
+					// See the NOTE in "ebs_block_device" for why we skip iops
+					// and encrypted here.
 					// buf.WriteString(fmt.Sprintf("%d-", m["iops"].(int)))
 					buf.WriteString(fmt.Sprintf("%d-", m["volume_size"].(int)))
 					buf.WriteString(fmt.Sprintf("%s-", m["volume_type"].(string)))
+					// buf.WriteString(fmt.Sprintf("%t-", m["encrypted"].(bool)))
 					return hashcode.String(buf.String())
 				},
 			},
@@ -254,14 +410,26 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 		userData = base64.StdEncoding.EncodeToString([]byte(v.(string)))
 	}
 
+	var lcName string
+	if v, ok := d.GetOk("name"); ok {
+		lcName = v.(string)
+	} else if v, ok := d.GetOk("name_prefix"); ok {
+		lcName = resource.PrefixedUniqueId(v.(string))
+	} else {
+		lcName = resource.UniqueId()
+	}
+
 	createLaunchConfigurationOpts := autoscaling.CreateLaunchConfigurationType{
-		LaunchConfigurationName: aws.String(d.Get("name").(string)),
+		LaunchConfigurationName: aws.String(lcName),
 		ImageID:                 aws.String(d.Get("image_id").(string)),
 		InstanceType:            aws.String(d.Get("instance_type").(string)),
 		UserData:                aws.String(userData),
 		EBSOptimized:            aws.Boolean(d.Get("ebs_optimized").(bool)),
 		IAMInstanceProfile:      aws.String(d.Get("iam_instance_profile").(string)),
 		PlacementTenancy:        aws.String(d.Get("placement_tenancy").(string)),
+		InstanceMonitoring: &autoscaling.InstanceMonitoring{
+			Enabled: aws.Boolean(d.Get("enable_monitoring").(bool)),
+		},
 	}
 
 	if v := d.Get("associate_public_ip_address"); v != nil {
@@ -309,6 +477,14 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 				ebs.IOPS = aws.Integer(v)
 			}
 
+			if v, ok := bd["encrypted"].(bool); ok && v {
+				ebs.Encrypted = aws.Boolean(v)
+			}
+
+			if v, ok := bd["kms_key_id"].(string); ok && v != "" {
+				ebs.KMSKeyID = aws.String(v)
+			}
+
 			blockDevices = append(blockDevices, autoscaling.BlockDeviceMapping{
 				DeviceName: aws.String(bd["device_name"].(string)),
 				EBS:        ebs,
@@ -350,7 +526,15 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 				ebs.IOPS = aws.Integer(v)
 			}
 
-			if dn, err := fetchRootDeviceName(d.Get("ami").(string), meta.(*AWSClient).ec2conn); err == nil {
+			if v, ok := bd["encrypted"].(bool); ok && v {
+				ebs.Encrypted = aws.Boolean(v)
+			}
+
+			if v, ok := bd["kms_key_id"].(string); ok && v != "" {
+				ebs.KMSKeyID = aws.String(v)
+			}
+
+			if dn, err := fetchRootDeviceName(d.Get("image_id").(string), meta.(*AWSClient).ec2conn); err == nil {
 				blockDevices = append(blockDevices, autoscaling.BlockDeviceMapping{
 					DeviceName: dn,
 					EBS:        ebs,
@@ -371,7 +555,8 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 		return fmt.Errorf("Error creating launch configuration: %s", err)
 	}
 
-	d.SetId(d.Get("name").(string))
+	d.SetId(lcName)
+	d.Set("name", lcName)
 	log.Printf("[INFO] launch configuration ID: %s", d.Id())
 
 	// We put a Retry here since sometimes eventual consistency bites
@@ -383,6 +568,7 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 
 func resourceAwsLaunchConfigurationRead(d *schema.ResourceData, meta interface{}) error {
 	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	ec2conn := meta.(*AWSClient).ec2conn
 
 	describeOpts := autoscaling.LaunchConfigurationNamesType{
 		LaunchConfigurationNames: []string{d.Id()},
@@ -412,16 +598,25 @@ func resourceAwsLaunchConfigurationRead(d *schema.ResourceData, meta interface{}
 	d.Set("instance_type", *lc.InstanceType)
 	d.Set("name", *lc.LaunchConfigurationName)
 
-	bds := make([]map[string]interface{}, len(lc.BlockDeviceMappings))
-	for i, m := range lc.BlockDeviceMappings {
-		bds[i] = make(map[string]interface{})
-		bds[i]["device_name"] = m.DeviceName
-		bds[i]["snapshot_id"] = m.EBS.SnapshotID
-		bds[i]["volume_type"] = m.EBS.VolumeType
-		bds[i]["volume_size"] = m.EBS.VolumeSize
-		bds[i]["delete_on_termination"] = m.EBS.DeleteOnTermination
+	if lc.InstanceMonitoring != nil && lc.InstanceMonitoring.Enabled != nil {
+		d.Set("enable_monitoring", *lc.InstanceMonitoring.Enabled)
 	}
-	d.Set("block_device", bds)
+
+	if lc.EBSOptimized != nil {
+		d.Set("ebs_optimized", *lc.EBSOptimized)
+	}
+
+	if lc.PlacementTenancy != nil {
+		d.Set("placement_tenancy", *lc.PlacementTenancy)
+	}
+
+	blockDevices, err := readBlockDevicesFromLaunchConfiguration(d, &lc, ec2conn)
+	if err != nil {
+		return err
+	}
+	d.Set("ebs_block_device", blockDevices["ebs_block_device"])
+	d.Set("ephemeral_block_device", blockDevices["ephemeral_block_device"])
+	d.Set("root_block_device", blockDevices["root_block_device"])
 
 	if lc.IAMInstanceProfile != nil {
 		d.Set("iam_instance_profile", *lc.IAMInstanceProfile)
@@ -462,8 +657,98 @@ func resourceAwsLaunchConfigurationDelete(d *schema.ResourceData, meta interface
 	return nil
 }
 
-func readBlockDevicesFromLaunchConfiguration(d *schema.ResourceData, launchConfiguration *autoscaling.LaunchConfiguration, autoscalingconn *autoscaling.AutoScaling) map[string]interface{} {
-	var blockDevices = make(map[string]interface{})
-	// Need to figure out how to determine the various instance types.
-	return blockDevices
+func readBlockDevicesFromLaunchConfiguration(d *schema.ResourceData, lc *autoscaling.LaunchConfiguration, ec2conn *ec2.EC2) (map[string]interface{}, error) {
+	blockDevices := make(map[string]interface{})
+	blockDevices["ebs_block_device"] = make([]map[string]interface{}, 0)
+	blockDevices["ephemeral_block_device"] = make([]map[string]interface{}, 0)
+	blockDevices["root_block_device"] = make([]map[string]interface{}, 0)
+
+	rootDeviceName, err := fetchRootDeviceName(*lc.ImageID, ec2conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bdm := range lc.BlockDeviceMappings {
+		// Seed every key the ebs_block_device/root_block_device Set hash
+		// funcs dereference so a plain (non-snapshot, non-encrypted) volume
+		// doesn't leave any of them absent from the map -- a missing key
+		// fails the hash func's bare type assertion.
+		bd := map[string]interface{}{
+			"delete_on_termination": false,
+			"device_name":           "",
+			"snapshot_id":           "",
+			"volume_size":           0,
+			"volume_type":           "",
+			"encrypted":             false,
+		}
+
+		if bdm.EBS != nil && bdm.EBS.DeleteOnTermination != nil {
+			bd["delete_on_termination"] = *bdm.EBS.DeleteOnTermination
+		}
+		if bdm.EBS != nil && bdm.EBS.VolumeSize != nil {
+			bd["volume_size"] = *bdm.EBS.VolumeSize
+		}
+		if bdm.EBS != nil && bdm.EBS.VolumeType != nil {
+			bd["volume_type"] = *bdm.EBS.VolumeType
+		}
+		if bdm.EBS != nil && bdm.EBS.IOPS != nil {
+			bd["iops"] = *bdm.EBS.IOPS
+		}
+		if bdm.EBS != nil && bdm.EBS.KMSKeyID != nil {
+			bd["kms_key_id"] = *bdm.EBS.KMSKeyID
+		}
+		// A volume created from an encrypted snapshot is encrypted even when
+		// the mapping itself doesn't set Encrypted explicitly, so fall back
+		// to that rather than defaulting to false and causing a perpetual diff.
+		if bdm.EBS != nil && bdm.EBS.Encrypted != nil {
+			bd["encrypted"] = *bdm.EBS.Encrypted
+		} else if bdm.EBS != nil && bdm.EBS.SnapshotID != nil {
+			if encrypted, err := fetchSnapshotEncrypted(*bdm.EBS.SnapshotID, ec2conn); err == nil {
+				bd["encrypted"] = encrypted
+			}
+		}
+
+		if bdm.VirtualName != nil && strings.HasPrefix(*bdm.VirtualName, "ephemeral") {
+			bd["device_name"] = *bdm.DeviceName
+			bd["virtual_name"] = *bdm.VirtualName
+			blockDevices["ephemeral_block_device"] = append(
+				blockDevices["ephemeral_block_device"].([]map[string]interface{}), bd)
+			continue
+		}
+
+		if rootDeviceName != nil && bdm.DeviceName != nil && *bdm.DeviceName == *rootDeviceName {
+			blockDevices["root_block_device"] = append(
+				blockDevices["root_block_device"].([]map[string]interface{}), bd)
+			continue
+		}
+
+		if bdm.DeviceName != nil {
+			bd["device_name"] = *bdm.DeviceName
+		}
+		if bdm.EBS != nil && bdm.EBS.SnapshotID != nil {
+			bd["snapshot_id"] = *bdm.EBS.SnapshotID
+		}
+		blockDevices["ebs_block_device"] = append(
+			blockDevices["ebs_block_device"].([]map[string]interface{}), bd)
+	}
+
+	return blockDevices, nil
+}
+
+// fetchSnapshotEncrypted looks up whether a given EBS snapshot is encrypted,
+// used to infer the implicit encryption of a volume restored from it.
+func fetchSnapshotEncrypted(snapshotID string, ec2conn *ec2.EC2) (bool, error) {
+	snapshots, err := ec2conn.DescribeSnapshots(&ec2.DescribeSnapshotsRequest{
+		SnapshotIDs: []string{snapshotID},
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(snapshots.Snapshots) == 0 {
+		return false, fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+	if snapshots.Snapshots[0].Encrypted == nil {
+		return false, nil
+	}
+	return *snapshots.Snapshots[0].Encrypted, nil
 }
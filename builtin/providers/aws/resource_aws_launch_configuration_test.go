@@ -0,0 +1,73 @@
+package aws
+
+import "testing"
+
+func TestValidateLaunchConfigurationImageId(t *testing.T) {
+	validateFunc := resourceAwsLaunchConfiguration().Schema["image_id"].ValidateFunc
+
+	validIds := []string{
+		"ami-12345678",
+		"ami-1234567890abcdef0",
+	}
+	for _, v := range validIds {
+		_, errors := validateFunc(v, "image_id")
+		if len(errors) != 0 {
+			t.Errorf("%q should be a valid AMI id: %q", v, errors)
+		}
+	}
+
+	invalidIds := []string{
+		"",
+		"ami-123",
+		"not-an-ami",
+		"ami_12345678",
+	}
+	for _, v := range invalidIds {
+		_, errors := validateFunc(v, "image_id")
+		if len(errors) == 0 {
+			t.Errorf("%q should not be a valid AMI id", v)
+		}
+	}
+}
+
+func TestValidateLaunchConfigurationInstanceType(t *testing.T) {
+	validateFunc := resourceAwsLaunchConfiguration().Schema["instance_type"].ValidateFunc
+
+	for v := range validEc2InstanceTypes {
+		_, errors := validateFunc(v, "instance_type")
+		if len(errors) != 0 {
+			t.Errorf("%q should be a recognized instance type: %q", v, errors)
+		}
+	}
+
+	invalidTypes := []string{
+		"",
+		"m9.bogus",
+		"t2.nano.",
+		"not-an-instance-type",
+	}
+	for _, v := range invalidTypes {
+		_, errors := validateFunc(v, "instance_type")
+		if len(errors) == 0 {
+			t.Errorf("%q should not be a recognized instance type", v)
+		}
+	}
+}
+
+func TestValidateLaunchConfigurationPlacementTenancy(t *testing.T) {
+	validateFunc := resourceAwsLaunchConfiguration().Schema["placement_tenancy"].ValidateFunc
+
+	for _, v := range []string{"default", "dedicated"} {
+		_, errors := validateFunc(v, "placement_tenancy")
+		if len(errors) != 0 {
+			t.Errorf("%q should be a valid placement_tenancy: %q", v, errors)
+		}
+	}
+
+	for _, v := range []string{"", "host", "Default"} {
+		_, errors := validateFunc(v, "placement_tenancy")
+		if len(errors) == 0 {
+			t.Errorf("%q should not be a valid placement_tenancy", v)
+		}
+	}
+}